@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/drud/ddev/pkg/plugins/platform"
+)
+
+// Exit codes for platform.Error failures, so shell scripts driving ddev can
+// branch on failure kind instead of scraping error text. 0 and 1 are left
+// alone (success / generic failure) to match normal CLI conventions.
+const (
+	ExitCodeNoConfig              = 10
+	ExitCodeDockerUnavailable     = 11
+	ExitCodeContainerStopFailed   = 12
+	ExitCodeContainerRemoveFailed = 13
+	ExitCodeVolumeRemoveFailed    = 14
+	ExitCodePluginLookupFailed    = 15
+	ExitCodeSiteInitFailed        = 16
+)
+
+// exitCodesByErrorCode maps each platform.ErrorCode to the process exit code
+// a command should use when that error reaches the top level.
+var exitCodesByErrorCode = map[platform.ErrorCode]int{
+	platform.ErrNoConfig:              ExitCodeNoConfig,
+	platform.ErrDockerUnavailable:     ExitCodeDockerUnavailable,
+	platform.ErrContainerStopFailed:   ExitCodeContainerStopFailed,
+	platform.ErrContainerRemoveFailed: ExitCodeContainerRemoveFailed,
+	platform.ErrVolumeRemoveFailed:    ExitCodeVolumeRemoveFailed,
+	platform.ErrPluginLookupFailed:    ExitCodePluginLookupFailed,
+	platform.ErrSiteInitFailed:        ExitCodeSiteInitFailed,
+}
+
+// ExitCodeForError returns the process exit code a command should use for
+// err. A nil error gets 0; unrecognized errors get the generic failure code 1.
+func ExitCodeForError(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var platformErr *platform.Error
+	if errors.As(err, &platformErr) {
+		if code, ok := exitCodesByErrorCode[platformErr.Code]; ok {
+			return code
+		}
+	}
+
+	return 1
+}
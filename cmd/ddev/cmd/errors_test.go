@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/drud/ddev/pkg/plugins/platform"
+	asrt "github.com/stretchr/testify/assert"
+)
+
+func TestExitCodeForErrorMapsPlatformErrorCodes(t *testing.T) {
+	assert := asrt.New(t)
+
+	err := &platform.Error{Code: platform.ErrNoConfig, Site: "myproject", Err: errors.New("not found")}
+	assert.Equal(ExitCodeNoConfig, ExitCodeForError(err))
+
+	err = &platform.Error{Code: platform.ErrDockerUnavailable, Err: errors.New("no daemon")}
+	assert.Equal(ExitCodeDockerUnavailable, ExitCodeForError(err))
+}
+
+func TestExitCodeForErrorMapsWrappedPlatformError(t *testing.T) {
+	assert := asrt.New(t)
+
+	cause := &platform.Error{Code: platform.ErrSiteInitFailed, Site: "myproject", Err: errors.New("timed out")}
+	wrapped := fmt.Errorf("loading projects: %w", cause)
+
+	var platformErr *platform.Error
+	assert.True(errors.As(wrapped, &platformErr))
+	assert.Equal(ExitCodeSiteInitFailed, ExitCodeForError(wrapped))
+}
+
+func TestExitCodeForErrorDefaultsToGenericFailure(t *testing.T) {
+	assert := asrt.New(t)
+
+	assert.Equal(1, ExitCodeForError(errors.New("some unrelated error")))
+	assert.Equal(0, ExitCodeForError(nil))
+}
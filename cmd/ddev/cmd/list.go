@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/drud/ddev/pkg/plugins/platform"
+	"github.com/drud/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// listOutputFormat holds the value of the --output flag for ListCmd.
+var listOutputFormat string
+
+// ListCmd represents the list command
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List applications that exist locally",
+	Long:  `List applications that exist locally.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apps, err := platform.GetAppsErr()
+		if err != nil {
+			util.Warning("One or more ddev projects could not be loaded: %v", err)
+			if len(apps) < 1 {
+				os.Exit(ExitCodeForError(err))
+			}
+		}
+
+		if len(apps) < 1 {
+			util.Success("There are no running ddev projects.")
+			return
+		}
+
+		switch listOutputFormat {
+		case "json":
+			if err := platform.RenderAppJSON(flattenApps(apps)); err != nil {
+				util.Failed("Failed to render app list as JSON: %v", err)
+			}
+		case "yaml":
+			if err := platform.RenderAppYAML(flattenApps(apps)); err != nil {
+				util.Failed("Failed to render app list as YAML: %v", err)
+			}
+		case "":
+			for platformType, platformApps := range apps {
+				platform.RenderAppTable(platformType, platformApps)
+			}
+		default:
+			util.Failed("Unknown --output format %q, must be one of: json, yaml", listOutputFormat)
+		}
+	},
+}
+
+func init() {
+	ListCmd.Flags().StringVarP(&listOutputFormat, "output", "o", "", "Output format: json, yaml (default is a human-readable table)")
+	RootCmd.AddCommand(ListCmd)
+}
+
+// flattenApps merges every platform's apps into a single slice, so JSON/YAML
+// output is one stable document rather than one per platform type.
+func flattenApps(apps map[string][]platform.App) []platform.App {
+	all := make([]platform.App, 0, len(apps))
+	for _, platformApps := range apps {
+		all = append(all, platformApps...)
+	}
+	return all
+}
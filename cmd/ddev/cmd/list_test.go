@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/drud/ddev/pkg/exec"
@@ -26,5 +27,40 @@ func TestDevList(t *testing.T) {
 		assert.Contains(string(out), platform.RenderHomeRootedDir(app.AppRoot()))
 		cleanup()
 	}
+}
+
+// TestDevListJSON ensures `ddev list --output=json` emits a stable,
+// machine-readable schema for each running app.
+func TestDevListJSON(t *testing.T) {
+	assert := asrt.New(t)
+	args := []string{"list", "--output=json"}
+	out, err := exec.RunCommand(DdevBin, args)
+	assert.NoError(err)
+
+	var items []platform.AppListItem
+	err = json.Unmarshal(out, &items)
+	assert.NoError(err)
+
+	for _, v := range DevTestSites {
+		cleanup := v.Chdir()
 
+		app, err := platform.GetActiveApp("")
+		if err != nil {
+			assert.Fail("Could not find an active ddev configuration: %v", err)
+		}
+
+		var found *platform.AppListItem
+		for i := range items {
+			if items[i].Name == v.Name {
+				found = &items[i]
+				break
+			}
+		}
+		if assert.NotNil(found, "expected to find %s in JSON output", v.Name) {
+			assert.Equal(app.GetType(), found.Type)
+			assert.Equal(app.URL(), found.URL)
+			assert.Equal(platform.RenderHomeRootedDir(app.AppRoot()), platform.RenderHomeRootedDir(found.ApprootPath))
+		}
+		cleanup()
+	}
 }
@@ -0,0 +1,135 @@
+package platform
+
+import (
+	"os"
+
+	"github.com/drud/ddev/pkg/truncindex"
+)
+
+// AppIndex builds a truncindex.TruncIndex over the names of every currently
+// discovered app, so callers can resolve a site by any unambiguous prefix of
+// its name instead of requiring the full name.
+func AppIndex() *truncindex.TruncIndex {
+	return truncindex.NewTruncIndex(AppNames())
+}
+
+// AppNames returns the names of every currently discovered app across all
+// platform types.
+func AppNames() []string {
+	names := []string{}
+	for _, platformApps := range GetApps() {
+		for _, site := range platformApps {
+			names = append(names, site.GetName())
+		}
+	}
+	return names
+}
+
+// ResolveAppName resolves name to exactly one known app, allowing users to
+// refer to a project by any unambiguous prefix of its name, e.g.
+// `ddev stop myp` matching `myproject`. An exact name match always wins over
+// a prefix match.
+func ResolveAppName(name string) (string, error) {
+	return resolveAppName(name, AppNames())
+}
+
+// resolveAppName is the testable core of ResolveAppName: given an explicit
+// set of names, find the one unambiguously identified by name.
+func resolveAppName(name string, names []string) (string, error) {
+	if name == "" {
+		return "", truncindex.ErrEmptyPrefix
+	}
+	for _, n := range names {
+		if n == name {
+			return n, nil
+		}
+	}
+	return truncindex.NewTruncIndex(names).Get(name)
+}
+
+// CleanupByName resolves nameOrPrefix to a known app and cleans it up,
+// allowing callers to refer to a project by any unambiguous prefix of its
+// name, the same way ResolveAppName does for other site-name commands.
+func CleanupByName(nameOrPrefix string) error {
+	site, err := findAppByResolvedName(nameOrPrefix)
+	if err != nil {
+		return err
+	}
+	return Cleanup(site)
+}
+
+// GetActiveApp returns the App for siteName, resolved through ResolveAppName
+// so any unambiguous prefix of a known site's name is accepted. If siteName
+// is empty, it instead returns the app rooted at (or above) the current
+// working directory, the way every CLI command that takes an optional site
+// name argument is expected to behave.
+func GetActiveApp(siteName string) (App, error) {
+	if siteName == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		approot, err := CheckForConf(cwd)
+		if err != nil {
+			return nil, err
+		}
+		return findAppByApproot(approot)
+	}
+
+	return findAppByResolvedName(siteName)
+}
+
+// findAppByResolvedName resolves nameOrPrefix via ResolveAppName and returns
+// the matching App, so every call site that takes a site-name argument goes
+// through the same prefix-resolution path.
+func findAppByResolvedName(nameOrPrefix string) (App, error) {
+	name, err := ResolveAppName(nameOrPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, platformApps := range GetApps() {
+		for _, site := range platformApps {
+			if site.GetName() == name {
+				return site, nil
+			}
+		}
+	}
+
+	return nil, &Error{Code: ErrNoConfig, Site: name, Err: truncindex.ErrNotExist}
+}
+
+// findAppByApproot returns the App rooted at approot, for the no-argument
+// form of GetActiveApp. It first checks already-discovered, running sites,
+// then falls back to constructing and Init()-ing the App directly, since a
+// project that has never been started yet has no container for GetApps to
+// discover it by.
+func findAppByApproot(approot string) (App, error) {
+	for _, platformApps := range GetApps() {
+		for _, site := range platformApps {
+			if site.AppRoot() == approot {
+				return site, nil
+			}
+		}
+	}
+
+	return initAppFromApproot(approot)
+}
+
+// initAppFromApproot constructs and Init()s an App rooted at approot
+// directly, mirroring discoverSite's construction of an App from a running
+// container, but for the case where no such container exists yet.
+func initAppFromApproot(approot string) (App, error) {
+	for platformType := range PluginMap {
+		site, err := GetPluginApp(platformType)
+		if err != nil {
+			continue
+		}
+		if err := site.Init(approot); err != nil {
+			continue
+		}
+		return site, nil
+	}
+
+	return nil, &Error{Code: ErrNoConfig, Site: approot, Err: truncindex.ErrNotExist}
+}
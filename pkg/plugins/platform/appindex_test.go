@@ -0,0 +1,41 @@
+package platform
+
+import (
+	"testing"
+
+	asrt "github.com/stretchr/testify/assert"
+)
+
+func TestResolveAppNameExactMatch(t *testing.T) {
+	assert := asrt.New(t)
+	names := []string{"myproject", "otherproject"}
+
+	name, err := resolveAppName("myproject", names)
+	assert.NoError(err)
+	assert.Equal("myproject", name)
+}
+
+func TestResolveAppNameUniquePrefix(t *testing.T) {
+	assert := asrt.New(t)
+	names := []string{"myproject", "otherproject"}
+
+	name, err := resolveAppName("myp", names)
+	assert.NoError(err)
+	assert.Equal("myproject", name)
+}
+
+func TestResolveAppNameAmbiguousPrefix(t *testing.T) {
+	assert := asrt.New(t)
+	names := []string{"myproject", "myotherproject"}
+
+	_, err := resolveAppName("my", names)
+	assert.Error(err)
+}
+
+func TestResolveAppNameNoMatch(t *testing.T) {
+	assert := asrt.New(t)
+	names := []string{"myproject", "otherproject"}
+
+	_, err := resolveAppName("nope", names)
+	assert.Error(err)
+}
@@ -0,0 +1,66 @@
+package platform
+
+import "fmt"
+
+// ErrorCode identifies a stable category of platform failure, so callers
+// (and shell scripts driving ddev) can branch on the kind of failure rather
+// than parsing an error string.
+type ErrorCode string
+
+const (
+	// ErrNoConfig means no .ddev/config.yaml was found for the given site.
+	ErrNoConfig ErrorCode = "ErrNoConfig"
+	// ErrDockerUnavailable means the Docker daemon could not be reached.
+	ErrDockerUnavailable ErrorCode = "ErrDockerUnavailable"
+	// ErrContainerStopFailed means a container failed to stop during cleanup.
+	ErrContainerStopFailed ErrorCode = "ErrContainerStopFailed"
+	// ErrContainerRemoveFailed means a container failed to be removed during cleanup.
+	ErrContainerRemoveFailed ErrorCode = "ErrContainerRemoveFailed"
+	// ErrVolumeRemoveFailed means a volume failed to be removed during cleanup.
+	ErrVolumeRemoveFailed ErrorCode = "ErrVolumeRemoveFailed"
+	// ErrPluginLookupFailed means GetPluginApp could not produce an App for a discovered container.
+	ErrPluginLookupFailed ErrorCode = "ErrPluginLookupFailed"
+	// ErrSiteInitFailed means a site's App.Init() failed or timed out.
+	ErrSiteInitFailed ErrorCode = "ErrSiteInitFailed"
+)
+
+// Error is the typed error returned by platform functions in place of
+// ad-hoc fmt.Errorf strings. Code is stable across releases; Site and
+// ContainerID are populated when known, so callers using errors.As can
+// recover structured context instead of scraping the message.
+type Error struct {
+	Code        ErrorCode
+	Site        string
+	ContainerID string
+	Err         error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	msg := string(e.Code)
+	if e.Site != "" {
+		msg += fmt.Sprintf(" (site=%s)", e.Site)
+	}
+	if e.ContainerID != "" {
+		msg += fmt.Sprintf(" (container=%s)", e.ContainerID)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap allows errors.Is/errors.As to see through to the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is allows errors.Is(err, &platform.Error{Code: platform.ErrNoConfig}) to
+// match on Code alone, ignoring Site/ContainerID/Err.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Code == e.Code
+}
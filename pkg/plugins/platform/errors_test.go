@@ -0,0 +1,32 @@
+package platform
+
+import (
+	"errors"
+	"testing"
+
+	asrt "github.com/stretchr/testify/assert"
+)
+
+func TestCheckForConfErrorCode(t *testing.T) {
+	assert := asrt.New(t)
+
+	_, err := CheckForConf("/tmp/definitely-not-a-ddev-project")
+	assert.Error(err)
+
+	var platformErr *Error
+	assert.True(errors.As(err, &platformErr))
+	assert.Equal(ErrNoConfig, platformErr.Code)
+	assert.True(errors.Is(err, &Error{Code: ErrNoConfig}))
+	assert.False(errors.Is(err, &Error{Code: ErrDockerUnavailable}))
+}
+
+func TestErrorUnwrap(t *testing.T) {
+	assert := asrt.New(t)
+
+	cause := errors.New("boom")
+	err := &Error{Code: ErrVolumeRemoveFailed, Site: "myproject", Err: cause}
+
+	assert.Equal(cause, errors.Unwrap(err))
+	assert.Contains(err.Error(), "myproject")
+	assert.Contains(err.Error(), "boom")
+}
@@ -0,0 +1,159 @@
+package platform
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"regexp"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// validContainerNameChars mirrors the character set Docker itself accepts
+// for container/volume names, since generated and user-supplied site names
+// both end up as Docker labels.
+var validContainerNameChars = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// nameAdjectives and nameNouns back GenerateUniqueSiteName. They're kept
+// short and memorable rather than exhaustive.
+var nameAdjectives = [...]string{
+	"brave", "calm", "clever", "curious", "eager", "gentle", "happy", "jolly",
+	"kind", "lively", "lucky", "mighty", "nimble", "proud", "quiet", "quick",
+	"shiny", "silly", "sturdy", "sunny", "swift", "tidy", "witty", "zesty",
+}
+
+var nameNouns = [...]string{
+	"badger", "dolphin", "falcon", "fox", "heron", "koala", "lemur", "lynx",
+	"marmot", "meerkat", "narwhal", "otter", "panda", "penguin", "raccoon",
+	"seahorse", "sparrow", "tiger", "toucan", "walrus", "weasel", "wombat",
+}
+
+// maxGenerateUniqueSiteNameAttempts bounds retries before falling back to a
+// numeric suffix, so GenerateUniqueSiteName can never loop forever against a
+// pathological existing list.
+const maxGenerateUniqueSiteNameAttempts = 100
+
+// nameRand is a package-local source seeded from the current time. The
+// global math/rand source is not seeded anywhere in this codebase, so two
+// freshly-started ddev processes would otherwise always draw the same first
+// adjective/noun pair and collide on the same generated name.
+var nameRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+var nameRandMu sync.Mutex
+
+// nameIntn is a concurrency-safe wrapper around nameRand.Intn, since
+// *rand.Rand is not safe for concurrent use and GenerateUniqueSiteName may be
+// called from several goroutines at once.
+func nameIntn(n int) int {
+	nameRandMu.Lock()
+	defer nameRandMu.Unlock()
+	return nameRand.Intn(n)
+}
+
+// GenerateUniqueSiteName returns a memorable "adjective-noun" name that does
+// not collide with any name in existing. If the random word-pair space is
+// exhausted it falls back to appending a numeric suffix.
+func GenerateUniqueSiteName(existing []string) string {
+	taken := make(map[string]struct{}, len(existing))
+	for _, name := range existing {
+		taken[name] = struct{}{}
+	}
+
+	for i := 0; i < maxGenerateUniqueSiteNameAttempts; i++ {
+		name := randomSiteName()
+		if _, ok := taken[name]; !ok {
+			return name
+		}
+	}
+
+	// The word-pair space is exhausted (or existing is adversarially large);
+	// disambiguate with a numeric suffix until we find a free one.
+	base := randomSiteName()
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s-%d", base, i)
+		if _, ok := taken[name]; !ok {
+			return name
+		}
+	}
+}
+
+// randomSiteName returns a random "adjective-noun" pair, valid as-is against
+// validContainerNameChars.
+func randomSiteName() string {
+	return fmt.Sprintf("%s-%s", nameAdjectives[nameIntn(len(nameAdjectives))], nameNouns[nameIntn(len(nameNouns))])
+}
+
+// ValidateSiteName returns an error if name is not a valid Docker label
+// component, the same rule GenerateUniqueSiteName's output is held to.
+func ValidateSiteName(name string) error {
+	if !validContainerNameChars.MatchString(name) {
+		return fmt.Errorf("%s is not a valid site name: site names may only contain letters, digits, dashes, dots, and underscores, and must be at least two characters long", name)
+	}
+	return nil
+}
+
+// EnsureSiteName is the hook config-loading code should call once it reads
+// an app's configured name: it returns name unchanged when non-empty, or a
+// freshly generated unique one otherwise. Callers that persist the result
+// back to config.yaml are what makes an empty `name:` field self-healing.
+func EnsureSiteName(name string, existing []string) string {
+	if name != "" {
+		return name
+	}
+	return GenerateUniqueSiteName(existing)
+}
+
+// ensureConfigSiteName reads confPath's .ddev/config.yaml and, if its `name:`
+// field is empty, generates a unique site name and persists it back to the
+// file. An existing name is returned as-is and unvalidated: CheckForConf (and
+// everything that calls it, which is nearly every command) just needs to
+// locate a project, not enforce a naming rule that didn't exist when the
+// project was configured. ValidateSiteName is for call sites where a user is
+// actively supplying a new name, such as `ddev config --name`.
+func ensureConfigSiteName(confPath string) (string, error) {
+	configFile := confPath + "/.ddev/config.yaml"
+
+	raw, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return "", &Error{Code: ErrNoConfig, Site: confPath, Err: err}
+	}
+
+	var doc struct {
+		Name string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+
+	if doc.Name != "" {
+		return doc.Name, nil
+	}
+
+	name := GenerateUniqueSiteName(AppNames())
+	if err := ioutil.WriteFile(configFile, setConfigNameLine(raw, name), 0644); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// configNameLineRe matches a config.yaml `name:` field line, letting
+// setConfigNameLine rewrite just that line in place.
+var configNameLineRe = regexp.MustCompile(`(?m)^name:.*$`)
+
+// setConfigNameLine returns raw with its `name:` field set to name, adding
+// the field if it's missing. It edits only that one line rather than
+// round-tripping the whole document through gopkg.in/yaml.v2, which has no
+// comment model and would otherwise silently strip every comment from a
+// user's config.yaml the first time a blank name got filled in.
+func setConfigNameLine(raw []byte, name string) []byte {
+	line := []byte("name: " + name)
+	if configNameLineRe.Match(raw) {
+		return configNameLineRe.ReplaceAll(raw, line)
+	}
+	if len(raw) > 0 && raw[len(raw)-1] != '\n' {
+		raw = append(raw, '\n')
+	}
+	return append(append(raw, line...), '\n')
+}
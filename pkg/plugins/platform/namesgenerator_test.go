@@ -0,0 +1,113 @@
+package platform
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	asrt "github.com/stretchr/testify/assert"
+)
+
+func TestGenerateUniqueSiteNameIsValid(t *testing.T) {
+	assert := asrt.New(t)
+	name := GenerateUniqueSiteName(nil)
+	assert.NoError(ValidateSiteName(name))
+}
+
+func TestGenerateUniqueSiteNameAvoidsCollisions(t *testing.T) {
+	assert := asrt.New(t)
+	existing := []string{}
+	for i := 0; i < 50; i++ {
+		name := GenerateUniqueSiteName(existing)
+		assert.NotContains(existing, name)
+		existing = append(existing, name)
+	}
+}
+
+func TestValidateSiteName(t *testing.T) {
+	assert := asrt.New(t)
+
+	assert.NoError(ValidateSiteName("my-project"))
+	assert.NoError(ValidateSiteName("my_project.1"))
+
+	assert.Error(ValidateSiteName(""))
+	assert.Error(ValidateSiteName("a"))
+	assert.Error(ValidateSiteName("-leading-dash"))
+	assert.Error(ValidateSiteName("has a space"))
+}
+
+func TestEnsureSiteName(t *testing.T) {
+	assert := asrt.New(t)
+
+	assert.Equal("existing-name", EnsureSiteName("existing-name", nil))
+
+	generated := EnsureSiteName("", []string{"taken-one"})
+	assert.NoError(ValidateSiteName(generated))
+	assert.NotEqual("taken-one", generated)
+}
+
+func TestEnsureConfigSiteNameGeneratesAndPersists(t *testing.T) {
+	assert := asrt.New(t)
+
+	confPath, err := ioutil.TempDir("", "ddev-namesgenerator-test-")
+	assert.NoError(err)
+	defer os.RemoveAll(confPath)
+
+	ddevDir := confPath + "/.ddev"
+	assert.NoError(os.MkdirAll(ddevDir, 0755))
+	assert.NoError(ioutil.WriteFile(ddevDir+"/config.yaml", []byte("name: \ntype: php\n"), 0644))
+
+	name, err := ensureConfigSiteName(confPath)
+	assert.NoError(err)
+	assert.NoError(ValidateSiteName(name))
+
+	persisted, err := ioutil.ReadFile(ddevDir + "/config.yaml")
+	assert.NoError(err)
+	assert.Contains(string(persisted), "name: "+name)
+	assert.Contains(string(persisted), "type: php")
+
+	// A second call should see the now-persisted name and leave it alone.
+	again, err := ensureConfigSiteName(confPath)
+	assert.NoError(err)
+	assert.Equal(name, again)
+}
+
+func TestEnsureConfigSiteNamePassesThroughExistingName(t *testing.T) {
+	assert := asrt.New(t)
+
+	confPath, err := ioutil.TempDir("", "ddev-namesgenerator-test-")
+	assert.NoError(err)
+	defer os.RemoveAll(confPath)
+
+	ddevDir := confPath + "/.ddev"
+	assert.NoError(os.MkdirAll(ddevDir, 0755))
+	// This name predates validContainerNameChars and would fail ValidateSiteName,
+	// but ensureConfigSiteName must not reject a project that already has one.
+	assert.NoError(ioutil.WriteFile(ddevDir+"/config.yaml", []byte("name: has a space\n"), 0644))
+
+	name, err := ensureConfigSiteName(confPath)
+	assert.NoError(err)
+	assert.Equal("has a space", name)
+}
+
+func TestEnsureConfigSiteNamePreservesComments(t *testing.T) {
+	assert := asrt.New(t)
+
+	confPath, err := ioutil.TempDir("", "ddev-namesgenerator-test-")
+	assert.NoError(err)
+	defer os.RemoveAll(confPath)
+
+	ddevDir := confPath + "/.ddev"
+	assert.NoError(os.MkdirAll(ddevDir, 0755))
+	original := "# This is the name of your ddev project.\nname: \n# The docroot is relative to this config file.\ntype: php\n"
+	assert.NoError(ioutil.WriteFile(ddevDir+"/config.yaml", []byte(original), 0644))
+
+	name, err := ensureConfigSiteName(confPath)
+	assert.NoError(err)
+
+	persisted, err := ioutil.ReadFile(ddevDir + "/config.yaml")
+	assert.NoError(err)
+	assert.Contains(string(persisted), "# This is the name of your ddev project.")
+	assert.Contains(string(persisted), "# The docroot is relative to this config file.")
+	assert.Contains(string(persisted), "name: "+name)
+}
@@ -1,14 +1,21 @@
 package platform
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/fsouza/go-dockerclient"
 	"github.com/gosuri/uitable"
+	"github.com/hashicorp/go-multierror"
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
 
 	"errors"
 
@@ -18,49 +25,231 @@ import (
 	gohomedir "github.com/mitchellh/go-homedir"
 )
 
-// GetApps returns a list of ddev applictions keyed by platform.
+// getAppsWorkerPoolSize bounds how many sites GetApps will Init() concurrently.
+const getAppsWorkerPoolSize = 10
+
+// getAppsSiteInitTimeout bounds how long GetApps will wait for a single
+// site's Init() before treating it as unhealthy and moving on.
+const getAppsSiteInitTimeout = 5 * time.Second
+
+// appCache holds the last-known result of a full GetApps discovery. It is
+// invalidated by Watcher whenever it observes a relevant config or Docker
+// event, so repeated calls from GetActiveApp/GetApps in between are cheap.
+var (
+	appCache      map[string][]App
+	appCacheValid bool
+	appCacheMu    sync.Mutex
+)
+
+// InvalidateAppCache discards the cached GetApps result, forcing the next
+// call to GetApps/GetAppsErr to re-run discovery. Watcher calls this
+// automatically as it observes changes.
+func InvalidateAppCache() {
+	appCacheMu.Lock()
+	defer appCacheMu.Unlock()
+	appCache = nil
+	appCacheValid = false
+}
+
+// AppListItem is a machine-readable, uncolored representation of a single
+// app, used to serialize `ddev list`/`ddev describe` output as JSON or YAML.
+type AppListItem struct {
+	Name              string   `json:"name" yaml:"name"`
+	Type              string   `json:"type" yaml:"type"`
+	ApprootPath       string   `json:"approot" yaml:"approot"`
+	URL               string   `json:"url" yaml:"url"`
+	Status            string   `json:"status" yaml:"status"`
+	RouterStatus      string   `json:"router_status" yaml:"router_status"`
+	ContainerIDs      []string `json:"container_ids" yaml:"container_ids"`
+	DockerComposeProj string   `json:"docker_compose_project" yaml:"docker_compose_project"`
+}
+
+// siteDiscoveryJob is the unit of work handed to the GetApps worker pool: one
+// container belonging to one platform type, waiting to be turned into an App.
+type siteDiscoveryJob struct {
+	platformType string
+	container    docker.APIContainers
+}
+
+// GetApps returns a list of ddev applications keyed by platform. Discovery
+// and per-site Init() fan out across a bounded worker pool so that one slow
+// or broken project does not stall the whole call; errors from individual
+// sites are collected rather than fatal. Use GetAppsErr if the aggregated
+// errors are needed; GetApps itself just logs them.
 func GetApps() map[string][]App {
-	apps := make(map[string][]App)
-	for platformType := range PluginMap {
-		labels := map[string]string{
-			"com.ddev.platform":          "ddev",
-			"com.docker.compose.service": "web",
-		}
-		sites, err := dockerutil.FindContainersByLabels(labels)
+	apps, err := GetAppsErr()
+	if err != nil {
+		log.Warnf("one or more ddev projects could not be loaded: %v", err)
+	}
+	return apps
+}
+
+// GetAppsErr behaves like GetApps but also returns a multi-error aggregating
+// every per-site failure encountered along the way, so callers can decide how
+// to react instead of the discovery silently swallowing (or fataling on) them.
+func GetAppsErr() (map[string][]App, error) {
+	appCacheMu.Lock()
+	if appCacheValid {
+		cached := appCache
+		appCacheMu.Unlock()
+		return cached, nil
+	}
+	appCacheMu.Unlock()
+
+	jobs := make(chan siteDiscoveryJob)
+	results := make(chan siteDiscoveryResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < getAppsWorkerPoolSize; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				results <- discoverSite(job)
+			}
+		}()
+	}
 
-		if err == nil {
+	go func() {
+		defer close(jobs)
+		for platformType := range PluginMap {
+			labels := map[string]string{
+				"com.ddev.platform":          "ddev",
+				"com.docker.compose.service": "web",
+			}
+			sites, err := dockerutil.FindContainersByLabels(labels)
+			if err != nil {
+				results <- siteDiscoveryResult{err: &Error{Code: ErrDockerUnavailable, Err: fmt.Errorf("listing containers for plugin type %s: %v", platformType, err)}}
+				continue
+			}
 			for _, siteContainer := range sites {
-				site, err := GetPluginApp(platformType)
-				// This should absolutely never happen, so just fatal on the off chance it does.
-				if err != nil {
-					log.Fatalf("could not get application for plugin type %s", platformType)
-				}
-				approot, ok := siteContainer.Labels["com.ddev.approot"]
-				if !ok {
-					break
-				}
-				_, ok = apps[platformType]
-				if !ok {
-					apps[platformType] = []App{}
-				}
-
-				err = site.Init(approot)
-				if err != nil {
-					// Cast 'site' from type App to type LocalApp, so we can manually enter AppConfig values.
-					siteStruct, ok := site.(*LocalApp)
-					if !ok {
-						log.Fatalf("Failed to cast siteStruct(type App) to *LocalApp{}. site=%v", site)
-					}
-
-					siteStruct.AppConfig.Name = siteContainer.Labels["com.ddev.site-name"]
-					siteStruct.AppConfig.AppType = siteContainer.Labels["com.ddev.app-type"]
-				}
-				apps[platformType] = append(apps[platformType], site)
+				jobs <- siteDiscoveryJob{platformType: platformType, container: siteContainer}
 			}
 		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	apps, err := aggregateDiscoveryResults(results)
+	if err == nil {
+		appCacheMu.Lock()
+		appCache = apps
+		appCacheValid = true
+		appCacheMu.Unlock()
 	}
 
-	return apps
+	return apps, err
+}
+
+// aggregateDiscoveryResults consumes every siteDiscoveryResult produced by
+// the worker pool and builds the map/multi-error GetAppsErr returns. Split
+// out from GetAppsErr so this aggregation logic is testable on its own,
+// without needing real plugins or a Docker daemon.
+func aggregateDiscoveryResults(results <-chan siteDiscoveryResult) (map[string][]App, error) {
+	apps := make(map[string][]App)
+	var errs *multierror.Error
+	for result := range results {
+		if result.err != nil {
+			errs = multierror.Append(errs, result.err)
+		}
+		if result.app != nil {
+			apps[result.platformType] = append(apps[result.platformType], result.app)
+		}
+	}
+	return apps, errs.ErrorOrNil()
+}
+
+// siteDiscoveryResult is produced by discoverSite for each siteDiscoveryJob.
+type siteDiscoveryResult struct {
+	platformType string
+	app          App
+	err          error
+}
+
+// discoverSite resolves a single container into an App, bounding Init() with
+// getAppsSiteInitTimeout so a slow filesystem or broken config can't stall
+// the worker pool indefinitely.
+func discoverSite(job siteDiscoveryJob) siteDiscoveryResult {
+	site, err := GetPluginApp(job.platformType)
+	if err != nil {
+		return siteDiscoveryResult{err: &Error{Code: ErrPluginLookupFailed, Err: fmt.Errorf("plugin type %s: %v", job.platformType, err)}}
+	}
+
+	approot, ok := job.container.Labels["com.ddev.approot"]
+	if !ok {
+		return siteDiscoveryResult{}
+	}
+
+	err = initSiteWithDeadline(site, approot, getAppsSiteInitTimeout)
+	switch err {
+	case nil:
+		return siteDiscoveryResult{platformType: job.platformType, app: site}
+	case errSiteInitTimeout:
+		// Init() is still running on its own goroutine and may still be
+		// writing into site; we no longer own it and must not touch it
+		// (not even to report the error fields ddev would normally fill
+		// in), so just report the failure and drop the site on the floor.
+		return siteDiscoveryResult{err: &Error{Code: ErrSiteInitFailed, Site: approot, Err: fmt.Errorf("timed out after %s", getAppsSiteInitTimeout)}}
+	default:
+		// Init() has actually returned, so we're the only goroutine that
+		// still holds a reference to site -- safe to fill in what we know
+		// about it from the container labels.
+		siteStruct, ok := site.(*LocalApp)
+		if !ok {
+			return siteDiscoveryResult{err: &Error{Code: ErrSiteInitFailed, Site: approot, Err: errors.New("failed to cast site (type App) to *LocalApp")}}
+		}
+
+		siteStruct.AppConfig.Name = job.container.Labels["com.ddev.site-name"]
+		siteStruct.AppConfig.AppType = job.container.Labels["com.ddev.app-type"]
+
+		return siteDiscoveryResult{
+			platformType: job.platformType,
+			app:          site,
+			err:          &Error{Code: ErrSiteInitFailed, Site: approot, Err: err},
+		}
+	}
+}
+
+// errSiteInitTimeout is returned by initSiteWithDeadline when site.Init()
+// did not complete within the deadline. It's a distinct sentinel (rather
+// than a generic error) so discoverSite can tell "Init is still running in
+// the background, don't touch site" apart from "Init actually returned an
+// error, safe to inspect/mutate site now".
+var errSiteInitTimeout = errors.New("timed out waiting for site to initialize")
+
+// initSiteWithDeadline calls site.Init(approot) but gives up after timeout,
+// so a single unhealthy project (slow mount, broken config) can't stall
+// GetApps for everyone else. The Init() goroutine is not cancelled on
+// timeout -- it keeps running in the background -- so callers must treat
+// errSiteInitTimeout as "site is no longer safe to touch", not just another
+// error.
+func initSiteWithDeadline(site App, approot string, timeout time.Duration) error {
+	return runWithDeadline(timeout, func() error {
+		return site.Init(approot)
+	})
+}
+
+// runWithDeadline runs fn on its own goroutine and returns its result, or
+// errSiteInitTimeout if fn hasn't finished within timeout. fn keeps running
+// after a timeout; this only stops waiting for it.
+func runWithDeadline(timeout time.Duration, fn func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errSiteInitTimeout
+	}
 }
 
 // RenderAppTable will format a table for user display based on a list of apps.
@@ -76,6 +265,62 @@ func RenderAppTable(platform string, apps []App) {
 	}
 }
 
+// BuildAppListItem assembles the machine-readable representation of a single
+// app, independent of any color or table formatting.
+func BuildAppListItem(site App) AppListItem {
+	labels := map[string]string{
+		"com.ddev.site-name": site.GetName(),
+	}
+	containerIDs := []string{}
+	containers, err := dockerutil.FindContainersByLabels(labels)
+	if err == nil {
+		for _, c := range containers {
+			containerIDs = append(containerIDs, c.ID)
+		}
+	}
+
+	return AppListItem{
+		Name:              site.GetName(),
+		Type:              site.GetType(),
+		ApprootPath:       site.AppRoot(),
+		URL:               site.URL(),
+		Status:            site.SiteStatus(),
+		RouterStatus:      stripColor(PrintRouterStatus()),
+		ContainerIDs:      containerIDs,
+		DockerComposeProj: "ddev" + strings.ToLower(site.GetName()),
+	}
+}
+
+// RenderAppJSON renders apps as a JSON array of AppListItem, bypassing the
+// color-wrapped status strings used by RenderAppTable.
+func RenderAppJSON(apps []App) error {
+	items := make([]AppListItem, 0, len(apps))
+	for _, site := range apps {
+		items = append(items, BuildAppListItem(site))
+	}
+	out, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// RenderAppYAML renders apps as a YAML array of AppListItem, bypassing the
+// color-wrapped status strings used by RenderAppTable.
+func RenderAppYAML(apps []App) error {
+	items := make([]AppListItem, 0, len(apps))
+	for _, site := range apps {
+		items = append(items, BuildAppListItem(site))
+	}
+	out, err := yaml.Marshal(items)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
 // CreateAppTable will create a new app table for describe and list output
 func CreateAppTable() *uitable.Table {
 	table := uitable.New()
@@ -131,7 +376,7 @@ func Cleanup(app App) error {
 	}
 	containers, err := dockerutil.FindContainersByLabels(labels)
 	if err != nil {
-		return err
+		return &Error{Code: ErrDockerUnavailable, Site: app.GetName(), Err: err}
 	}
 
 	// First, try stopping the listed containers if they are running.
@@ -141,7 +386,7 @@ func Cleanup(app App) error {
 			fmt.Printf("Stopping container: %s\n", containerName)
 			err = client.StopContainer(containers[i].ID, 60)
 			if err != nil {
-				return fmt.Errorf("could not stop container %s: %v", containerName, err)
+				return &Error{Code: ErrContainerStopFailed, Site: app.GetName(), ContainerID: containers[i].ID, Err: err}
 			}
 		}
 	}
@@ -156,20 +401,20 @@ func Cleanup(app App) error {
 		}
 		fmt.Printf("Removing container: %s\n", containerName)
 		if err = client.RemoveContainer(removeOpts); err != nil {
-			return fmt.Errorf("could not remove container %s: %v", containerName, err)
+			return &Error{Code: ErrContainerRemoveFailed, Site: app.GetName(), ContainerID: containers[i].ID, Err: err}
 		}
 	}
 
 	volumes, err := client.ListVolumes(docker.ListVolumesOptions{})
 	if err != nil {
-		return err
+		return &Error{Code: ErrDockerUnavailable, Site: app.GetName(), Err: err}
 	}
 
 	for _, volume := range volumes {
 		if volume.Labels["com.docker.compose.project"] == "ddev"+strings.ToLower(app.GetName()) {
 			err := client.RemoveVolume(volume.Name)
 			if err != nil {
-				return fmt.Errorf("could not remove volume %s: %v", volume.Name, err)
+				return &Error{Code: ErrVolumeRemoveFailed, Site: app.GetName(), Err: err}
 			}
 		}
 	}
@@ -177,21 +422,35 @@ func Cleanup(app App) error {
 	return StopRouter()
 }
 
-// CheckForConf checks for a config.yaml at the cwd or parent dirs.
+// CheckForConf checks for a config.yaml at the cwd or parent dirs. When it
+// finds one, it ensures the config's `name:` field is populated: a blank
+// name is generated via GenerateUniqueSiteName and persisted back to the
+// file, while an existing name is left as it is, without validation.
 func CheckForConf(confPath string) (string, error) {
 	if fileutil.FileExists(confPath + "/.ddev/config.yaml") {
-		return confPath, nil
+		return checkForConfEnsureName(confPath)
 	}
 	pathList := strings.Split(confPath, "/")
 
 	for range pathList {
 		confPath = filepath.Dir(confPath)
 		if fileutil.FileExists(confPath + "/.ddev/config.yaml") {
-			return confPath, nil
+			return checkForConfEnsureName(confPath)
 		}
 	}
 
-	return "", errors.New("no .ddev/config.yaml file was found in this directory or any parent")
+	return "", &Error{Code: ErrNoConfig, Site: confPath, Err: errors.New("no .ddev/config.yaml file was found in this directory or any parent")}
+}
+
+// checkForConfEnsureName runs ensureConfigSiteName for the config found at
+// confPath. A failure there (unreadable file, malformed YAML, failure to
+// write a generated name back) is surfaced as the error from CheckForConf
+// itself.
+func checkForConfEnsureName(confPath string) (string, error) {
+	if _, err := ensureConfigSiteName(confPath); err != nil {
+		return "", err
+	}
+	return confPath, nil
 }
 
 // ddevContainersRunning determines if any ddev-controlled containers are currently running.
@@ -208,3 +467,12 @@ func ddevContainersRunning() (bool, error) {
 	}
 	return false, nil
 }
+
+// ansiColorCodes matches the escape sequences emitted by github.com/fatih/color.
+var ansiColorCodes = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// stripColor removes ANSI color escape sequences so a string can be embedded
+// in machine-readable output such as JSON or YAML.
+func stripColor(s string) string {
+	return ansiColorCodes.ReplaceAllString(s, "")
+}
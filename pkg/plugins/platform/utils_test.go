@@ -0,0 +1,71 @@
+package platform
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	asrt "github.com/stretchr/testify/assert"
+)
+
+func TestRunWithDeadlineTimesOut(t *testing.T) {
+	assert := asrt.New(t)
+
+	err := runWithDeadline(10*time.Millisecond, func() error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	})
+
+	assert.Equal(errSiteInitTimeout, err)
+}
+
+func TestRunWithDeadlineReturnsUnderlyingError(t *testing.T) {
+	assert := asrt.New(t)
+
+	wantErr := errors.New("boom")
+	err := runWithDeadline(time.Second, func() error {
+		return wantErr
+	})
+
+	assert.Equal(wantErr, err)
+}
+
+func TestRunWithDeadlineReturnsNilOnSuccess(t *testing.T) {
+	assert := asrt.New(t)
+
+	err := runWithDeadline(time.Second, func() error {
+		return nil
+	})
+
+	assert.NoError(err)
+}
+
+func TestAggregateDiscoveryResultsCollectsErrors(t *testing.T) {
+	assert := asrt.New(t)
+
+	results := make(chan siteDiscoveryResult, 3)
+	results <- siteDiscoveryResult{err: &Error{Code: ErrDockerUnavailable, Err: errors.New("listing failed")}}
+	results <- siteDiscoveryResult{err: &Error{Code: ErrSiteInitFailed, Site: "myproject", Err: errors.New("init failed")}}
+	results <- siteDiscoveryResult{} // a container with no approot label: no app, no error
+	close(results)
+
+	apps, err := aggregateDiscoveryResults(results)
+	assert.Empty(apps)
+	assert.Error(err)
+
+	var merr *multierror.Error
+	assert.True(errors.As(err, &merr))
+	assert.Len(merr.Errors, 2)
+}
+
+func TestAggregateDiscoveryResultsNoErrors(t *testing.T) {
+	assert := asrt.New(t)
+
+	results := make(chan siteDiscoveryResult)
+	close(results)
+
+	apps, err := aggregateDiscoveryResults(results)
+	assert.NoError(err)
+	assert.Empty(apps)
+}
@@ -0,0 +1,230 @@
+package platform
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	docker "github.com/fsouza/go-dockerclient"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/drud/ddev/pkg/dockerutil"
+)
+
+// WatchEventType identifies what kind of change triggered a WatchEvent.
+type WatchEventType string
+
+const (
+	// WatchEventConfig fires when a watched approot's .ddev/config.yaml changes.
+	WatchEventConfig WatchEventType = "config"
+	// WatchEventDocker fires when the Docker daemon emits a container event.
+	WatchEventDocker WatchEventType = "docker"
+)
+
+// WatchEvent is emitted on a Watcher's channel whenever something that might
+// change the result of GetApps is observed.
+type WatchEvent struct {
+	Type        WatchEventType
+	Approot     string
+	DockerEvent *docker.APIEvents
+}
+
+// watcherDebounce is how long Watcher waits for a burst of related fsnotify
+// events (editors frequently write a file several times per save) to settle
+// before emitting a single WatchEvent.
+const watcherDebounce = 300 * time.Millisecond
+
+// watcherDockerReattachDelay is how long Watcher waits before trying to
+// re-attach its Docker event listener after the daemon connection drops.
+const watcherDockerReattachDelay = 2 * time.Second
+
+// Watcher observes every known approot's .ddev/config.yaml and the Docker
+// event stream, emitting a WatchEvent on changes and invalidating the GetApps
+// cache so callers see fresh results without polling. Config file changes are
+// debounced per approot; Docker events are not debounced and each one is
+// emitted as its own WatchEvent.
+type Watcher struct {
+	events chan WatchEvent
+	fsw    *fsnotify.Watcher
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+
+	// approotOf maps each watched directory (<approot>/.ddev) back to the
+	// approot it belongs to, so a raw fsnotify event path can be correlated
+	// to one of the approots NewWatcher was given.
+	approotOf map[string]string
+}
+
+// NewWatcher creates a Watcher observing config.yaml beneath each of approots.
+func NewWatcher(approots []string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	approotOf := make(map[string]string, len(approots))
+	for _, approot := range approots {
+		dir := approot + "/.ddev"
+		if err := fsw.Add(dir); err != nil {
+			log.Warnf("could not watch %s: %v", dir, err)
+			continue
+		}
+		approotOf[dir] = approot
+	}
+
+	w := &Watcher{
+		events:    make(chan WatchEvent),
+		fsw:       fsw,
+		stopCh:    make(chan struct{}),
+		approotOf: approotOf,
+	}
+	return w, nil
+}
+
+// approotForPath maps a raw fsnotify event path (e.g.
+// <approot>/.ddev/config.yaml) back to the approot NewWatcher registered it
+// under, falling back to the raw path itself if it doesn't match a watched
+// directory.
+func (w *Watcher) approotForPath(path string) string {
+	for dir, approot := range w.approotOf {
+		if path == dir || strings.HasPrefix(path, dir+"/") {
+			return approot
+		}
+	}
+	return path
+}
+
+// Events returns the channel WatchEvents are emitted on.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Start begins watching in the background. It returns immediately; call
+// Stop to shut the watcher down.
+func (w *Watcher) Start() {
+	w.wg.Add(2)
+	go w.watchFs()
+	go w.watchDocker()
+}
+
+// Stop shuts the watcher down and closes its event channel. It blocks until
+// both the filesystem and Docker watch loops have exited.
+func (w *Watcher) Stop() {
+	close(w.stopCh)
+	w.wg.Wait()
+	_ = w.fsw.Close()
+	close(w.events)
+}
+
+// emit delivers ev on w.events, giving up if Stop is called first so a
+// consumer that's stopped reading can never deadlock shutdown.
+func (w *Watcher) emit(ev WatchEvent) {
+	select {
+	case w.events <- ev:
+	case <-w.stopCh:
+	}
+}
+
+// watchFs debounces bursts of fsnotify events per-approot and emits one
+// WatchEvent (invalidating the app cache) once each burst settles.
+func (w *Watcher) watchFs() {
+	defer w.wg.Done()
+
+	pending := map[string]*time.Timer{}
+	fire := make(chan string)
+
+	for {
+		select {
+		case <-w.stopCh:
+			for _, t := range pending {
+				t.Stop()
+			}
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				continue
+			}
+			approot := w.approotForPath(ev.Name)
+			if t, exists := pending[approot]; exists {
+				t.Reset(watcherDebounce)
+				continue
+			}
+			pending[approot] = time.AfterFunc(watcherDebounce, func() {
+				fire <- approot
+			})
+		case err, ok := <-w.fsw.Errors:
+			if ok {
+				log.Warnf("fsnotify watch error: %v", err)
+			}
+		case approot := <-fire:
+			delete(pending, approot)
+			InvalidateAppCache()
+			w.emit(WatchEvent{Type: WatchEventConfig, Approot: approot})
+		}
+	}
+}
+
+// watchDocker attaches a Docker event listener and re-attaches with a short
+// delay whenever the connection to the daemon drops, so a daemon restart
+// doesn't permanently stop event delivery.
+func (w *Watcher) watchDocker() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		default:
+		}
+
+		client := dockerutil.GetDockerClient()
+		listener := make(chan *docker.APIEvents)
+		if err := client.AddEventListener(listener); err != nil {
+			log.Warnf("could not attach docker event listener: %v", err)
+			if !w.sleepOrStop(watcherDockerReattachDelay) {
+				return
+			}
+			continue
+		}
+
+		w.drainDockerEvents(client, listener)
+		if !w.sleepOrStop(watcherDockerReattachDelay) {
+			return
+		}
+	}
+}
+
+// sleepOrStop sleeps for d, returning early with false if Stop is called
+// first.
+func (w *Watcher) sleepOrStop(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-w.stopCh:
+		return false
+	}
+}
+
+// drainDockerEvents forwards Docker events to w.events until the listener
+// dies or Stop is called.
+func (w *Watcher) drainDockerEvents(client *docker.Client, listener chan *docker.APIEvents) {
+	defer func() {
+		_ = client.RemoveEventListener(listener)
+	}()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case event, ok := <-listener:
+			if !ok {
+				return
+			}
+			InvalidateAppCache()
+			w.emit(WatchEvent{Type: WatchEventDocker, DockerEvent: event})
+		}
+	}
+}
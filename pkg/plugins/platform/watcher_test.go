@@ -0,0 +1,72 @@
+package platform
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	asrt "github.com/stretchr/testify/assert"
+)
+
+func TestWatcherDebouncesBurstsPerApproot(t *testing.T) {
+	assert := asrt.New(t)
+
+	approot, err := ioutil.TempDir("", "ddev-watcher-test-")
+	assert.NoError(err)
+	defer os.RemoveAll(approot)
+	assert.NoError(os.MkdirAll(approot+"/.ddev", 0755))
+
+	w, err := NewWatcher([]string{approot})
+	assert.NoError(err)
+	w.Start()
+	defer w.Stop()
+
+	configFile := approot + "/.ddev/config.yaml"
+	assert.NoError(ioutil.WriteFile(configFile, []byte("name: test\n"), 0644))
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(ioutil.WriteFile(configFile, []byte("name: test2\n"), 0644))
+
+	select {
+	case ev := <-w.Events():
+		assert.Equal(WatchEventConfig, ev.Type)
+		assert.Equal(approot, ev.Approot)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for debounced watch event")
+	}
+
+	// The two rapid writes should have collapsed into exactly one event.
+	select {
+	case ev := <-w.Events():
+		t.Fatalf("unexpected second event: %+v", ev)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestWatcherStopDrainsCleanly(t *testing.T) {
+	assert := asrt.New(t)
+
+	approot, err := ioutil.TempDir("", "ddev-watcher-test-")
+	assert.NoError(err)
+	defer os.RemoveAll(approot)
+	assert.NoError(os.MkdirAll(approot+"/.ddev", 0755))
+
+	w, err := NewWatcher([]string{approot})
+	assert.NoError(err)
+	w.Start()
+
+	done := make(chan struct{})
+	go func() {
+		w.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return promptly")
+	}
+
+	_, ok := <-w.Events()
+	assert.False(ok, "Events() channel should be closed after Stop")
+}
@@ -0,0 +1,131 @@
+// Package truncindex provides a general-purpose toolkit that allows callers
+// to look up an identifier by any unambiguous prefix of it, the same way the
+// Docker daemon resolves short container and image IDs.
+package truncindex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tchap/go-patricia/patricia"
+)
+
+var (
+	// ErrEmptyPrefix is an error returned if the prefix was empty.
+	ErrEmptyPrefix = errors.New("prefix can't be empty")
+
+	// ErrIllegalChar is returned when a space is in the ID.
+	ErrIllegalChar = errors.New("illegal character: ' '")
+
+	// ErrNotExist is returned when the ID or its prefix is not found in the index.
+	ErrNotExist = errors.New("id does not exist")
+)
+
+// ErrAmbiguousPrefix is returned when a prefix matches more than one ID in
+// the index.
+type ErrAmbiguousPrefix struct {
+	prefix string
+}
+
+// Error implements the error interface for ErrAmbiguousPrefix.
+func (e ErrAmbiguousPrefix) Error() string {
+	return fmt.Sprintf("ambiguous ID prefix: %s", e.prefix)
+}
+
+// TruncIndex allows the retrieval of string identifiers by any of their
+// unique prefixes. It can be used to dynamically compact a list of
+// full-length IDs as more unique identifiers are added.
+type TruncIndex struct {
+	sync.RWMutex
+	trie *patricia.Trie
+	ids  map[string]struct{}
+}
+
+// NewTruncIndex creates a new TruncIndex and populates it with ids.
+func NewTruncIndex(ids []string) *TruncIndex {
+	idx := &TruncIndex{
+		ids:  make(map[string]struct{}),
+		trie: patricia.NewTrie(),
+	}
+	for _, id := range ids {
+		// Collisions and malformed entries are not fatal to index
+		// construction; callers that care can Add() them individually.
+		_ = idx.addID(id)
+	}
+	return idx
+}
+
+func (idx *TruncIndex) addID(id string) error {
+	if id == "" {
+		return ErrEmptyPrefix
+	}
+	if strings.Contains(id, " ") {
+		return ErrIllegalChar
+	}
+	if _, exists := idx.ids[id]; exists {
+		return fmt.Errorf("id already exists: '%s'", id)
+	}
+	idx.ids[id] = struct{}{}
+	if inserted := idx.trie.Insert(patricia.Prefix(id), struct{}{}); !inserted {
+		return fmt.Errorf("failed to insert id: %s", id)
+	}
+	return nil
+}
+
+// Add adds a new ID to the TruncIndex.
+func (idx *TruncIndex) Add(id string) error {
+	idx.Lock()
+	defer idx.Unlock()
+	return idx.addID(id)
+}
+
+// Delete removes an ID from the TruncIndex. If the ID does not exist, an
+// error is returned.
+func (idx *TruncIndex) Delete(id string) error {
+	idx.Lock()
+	defer idx.Unlock()
+	if _, exists := idx.ids[id]; !exists || id == "" {
+		return fmt.Errorf("no such id: '%s'", id)
+	}
+	delete(idx.ids, id)
+	if deleted := idx.trie.Delete(patricia.Prefix(id)); !deleted {
+		return fmt.Errorf("no such id: '%s'", id)
+	}
+	return nil
+}
+
+// Get retrieves an ID from the TruncIndex by exact ID or unambiguous prefix.
+// If prefix matches more than one ID, an ErrAmbiguousPrefix error is
+// returned; if it matches none, ErrNotExist is returned.
+func (idx *TruncIndex) Get(prefix string) (string, error) {
+	if prefix == "" {
+		return "", ErrEmptyPrefix
+	}
+
+	idx.RLock()
+	defer idx.RUnlock()
+
+	if _, exists := idx.ids[prefix]; exists {
+		return prefix, nil
+	}
+
+	var id string
+	visit := func(p patricia.Prefix, _ patricia.Item) error {
+		if id != "" {
+			id = ""
+			return ErrAmbiguousPrefix{prefix: prefix}
+		}
+		id = string(p)
+		return nil
+	}
+
+	if err := idx.trie.VisitSubtree(patricia.Prefix(prefix), visit); err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", ErrNotExist
+	}
+	return id, nil
+}
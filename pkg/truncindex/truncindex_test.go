@@ -0,0 +1,60 @@
+package truncindex
+
+import (
+	"testing"
+
+	asrt "github.com/stretchr/testify/assert"
+)
+
+func TestTruncIndexGetExactMatch(t *testing.T) {
+	assert := asrt.New(t)
+	idx := NewTruncIndex([]string{"myproject", "otherproject"})
+
+	id, err := idx.Get("myproject")
+	assert.NoError(err)
+	assert.Equal("myproject", id)
+}
+
+func TestTruncIndexGetUniquePrefix(t *testing.T) {
+	assert := asrt.New(t)
+	idx := NewTruncIndex([]string{"myproject", "otherproject"})
+
+	id, err := idx.Get("myp")
+	assert.NoError(err)
+	assert.Equal("myproject", id)
+}
+
+func TestTruncIndexGetAmbiguousPrefix(t *testing.T) {
+	assert := asrt.New(t)
+	idx := NewTruncIndex([]string{"myproject", "myotherproject"})
+
+	_, err := idx.Get("my")
+	assert.Error(err)
+	assert.IsType(ErrAmbiguousPrefix{}, err)
+}
+
+func TestTruncIndexGetNoMatch(t *testing.T) {
+	assert := asrt.New(t)
+	idx := NewTruncIndex([]string{"myproject", "otherproject"})
+
+	_, err := idx.Get("nope")
+	assert.Equal(ErrNotExist, err)
+}
+
+func TestTruncIndexAddAndDelete(t *testing.T) {
+	assert := asrt.New(t)
+	idx := NewTruncIndex([]string{"myproject"})
+
+	err := idx.Add("newproject")
+	assert.NoError(err)
+
+	id, err := idx.Get("new")
+	assert.NoError(err)
+	assert.Equal("newproject", id)
+
+	err = idx.Delete("newproject")
+	assert.NoError(err)
+
+	_, err = idx.Get("new")
+	assert.Equal(ErrNotExist, err)
+}